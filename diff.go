@@ -0,0 +1,161 @@
+package meshexp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MoveRecord describes a heading that occurs in both trees under the same name, but at a
+// different tree location.
+type MoveRecord struct {
+	Heading string
+	From    []string
+	To      []string
+}
+
+// RenameRecord describes a tree location that occurs in both trees, but under a different
+// heading. The rename is keyed on tree-location identity, not on heading text.
+type RenameRecord struct {
+	OldHeading string
+	NewHeading string
+	Location   []string
+}
+
+// MeSHDiff is the result of comparing two versions of a MeSHTree.
+type MeSHDiff struct {
+	Added   []TreeReference
+	Removed []TreeReference
+	Moved   []MoveRecord
+	Renamed []RenameRecord
+}
+
+// Diff compares old and new, the MeSHTree of an earlier and a later MeSH release, and reports
+// which headings were added, removed, moved to a different tree location, or renamed. A
+// heading is considered moved when the same heading occurs in both trees under a different
+// tree-number code, and renamed when the same tree-number code occurs in both trees under a
+// different heading. Anything left over is either newly added or removed outright.
+func Diff(old, newTree *MeSHTree) *MeSHDiff {
+	oldByCode := referencesByCode(old)
+	newByCode := referencesByCode(newTree)
+	oldByHeading := referencesByHeading(old)
+
+	matchedOldCodes := make(map[string]bool)
+	matchedNewCodes := make(map[string]bool)
+
+	diff := &MeSHDiff{}
+
+	// A tree-number code present in both trees is either unchanged or renamed.
+	for code, newRef := range newByCode {
+		oldRef, ok := oldByCode[code]
+		if !ok {
+			continue
+		}
+		matchedOldCodes[code] = true
+		matchedNewCodes[code] = true
+		if !strings.EqualFold(oldRef.MedicalSubjectHeading, newRef.MedicalSubjectHeading) {
+			diff.Renamed = append(diff.Renamed, RenameRecord{
+				OldHeading: oldRef.MedicalSubjectHeading,
+				NewHeading: newRef.MedicalSubjectHeading,
+				Location:   newRef.TreeLocation,
+			})
+		}
+	}
+
+	// A heading present in both trees, but not yet matched by code, has moved.
+	for code, newRef := range newByCode {
+		if matchedNewCodes[code] {
+			continue
+		}
+		oldRefs, ok := oldByHeading[strings.ToLower(newRef.MedicalSubjectHeading)]
+		if !ok {
+			continue
+		}
+		for _, oldRef := range oldRefs {
+			oldCode := strings.Join(oldRef.TreeLocation, ".")
+			if matchedOldCodes[oldCode] {
+				continue
+			}
+			diff.Moved = append(diff.Moved, MoveRecord{
+				Heading: newRef.MedicalSubjectHeading,
+				From:    oldRef.TreeLocation,
+				To:      newRef.TreeLocation,
+			})
+			matchedOldCodes[oldCode] = true
+			matchedNewCodes[code] = true
+			break
+		}
+	}
+
+	// Whatever is left in old but was never matched has been removed; whatever is left in new
+	// has been added.
+	for code, oldRef := range oldByCode {
+		if !matchedOldCodes[code] {
+			diff.Removed = append(diff.Removed, oldRef)
+		}
+	}
+	for code, newRef := range newByCode {
+		if !matchedNewCodes[code] {
+			diff.Added = append(diff.Added, newRef)
+		}
+	}
+
+	sortReferencesByCode(diff.Added)
+	sortReferencesByCode(diff.Removed)
+	sort.Slice(diff.Moved, func(i, j int) bool {
+		return strings.Join(diff.Moved[i].To, ".") < strings.Join(diff.Moved[j].To, ".")
+	})
+	sort.Slice(diff.Renamed, func(i, j int) bool {
+		return strings.Join(diff.Renamed[i].Location, ".") < strings.Join(diff.Renamed[j].Location, ".")
+	})
+
+	return diff
+}
+
+// Report renders d as a human-readable change report.
+func (d *MeSHDiff) Report() string {
+	var b strings.Builder
+	for _, ref := range d.Added {
+		fmt.Fprintf(&b, "+ %s (%s)\n", ref.MedicalSubjectHeading, strings.Join(ref.TreeLocation, "."))
+	}
+	for _, ref := range d.Removed {
+		fmt.Fprintf(&b, "- %s (%s)\n", ref.MedicalSubjectHeading, strings.Join(ref.TreeLocation, "."))
+	}
+	for _, m := range d.Moved {
+		fmt.Fprintf(&b, "~ %s: %s -> %s\n", m.Heading, strings.Join(m.From, "."), strings.Join(m.To, "."))
+	}
+	for _, r := range d.Renamed {
+		fmt.Fprintf(&b, "= %s -> %s (%s)\n", r.OldHeading, r.NewHeading, strings.Join(r.Location, "."))
+	}
+	return b.String()
+}
+
+// references walks t.Tree and returns every TreeReference it contains.
+func (t *MeSHTree) references() (refs []TreeReference) {
+	var walkTree func(tree Tree)
+	walkTree = func(tree Tree) {
+		for _, node := range tree {
+			refs = append(refs, node.Reference)
+			walkTree(node.Children)
+		}
+	}
+	walkTree(t.Tree)
+	return
+}
+
+func referencesByCode(t *MeSHTree) map[string]TreeReference {
+	byCode := make(map[string]TreeReference)
+	for _, ref := range t.references() {
+		byCode[strings.Join(ref.TreeLocation, ".")] = ref
+	}
+	return byCode
+}
+
+func referencesByHeading(t *MeSHTree) map[string][]TreeReference {
+	byHeading := make(map[string][]TreeReference)
+	for _, ref := range t.references() {
+		heading := strings.ToLower(ref.MedicalSubjectHeading)
+		byHeading[heading] = append(byHeading[heading], ref)
+	}
+	return byHeading
+}
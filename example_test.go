@@ -0,0 +1,42 @@
+package meshexp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExampleDiff demonstrates comparing two MeSH tree versions and rendering the result both as
+// JSON (for tooling) and as a human-readable report (for a changelog).
+func ExampleDiff() {
+	old, err := MeSHTreeFromReader(strings.NewReader(
+		"Pain;C23.888.592.612.705\n" +
+			"Neuralgia;C10.668.491.577\n",
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	updated, err := MeSHTreeFromReader(strings.NewReader(
+		"Pain;C23.888.592.612.705\n" +
+			"Neuralgia;C10.668.491.577.500\n" +
+			"Fatigue;C23.888.852.244\n",
+	))
+	if err != nil {
+		panic(err)
+	}
+
+	diff := Diff(old, updated)
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(data))
+	fmt.Print(diff.Report())
+
+	// Output:
+	// {"Added":[{"MedicalSubjectHeading":"Fatigue","TreeLocation":["C23","888","852","244"],"DescriptorUI":"","ScopeNote":"","EntryTerms":null}],"Removed":null,"Moved":[{"Heading":"Neuralgia","From":["C10","668","491","577"],"To":["C10","668","491","577","500"]}],"Renamed":null}
+	// + Fatigue (C23.888.852.244)
+	// ~ Neuralgia: C10.668.491.577 -> C10.668.491.577.500
+}
@@ -13,6 +13,13 @@ import (
 type TreeReference struct {
 	MedicalSubjectHeading string
 	TreeLocation          []string
+
+	// DescriptorUI, ScopeNote, and EntryTerms are only populated when the tree was loaded
+	// from the official NLM XML distribution via NewFromXML/MeSHTreeFromXMLReader; they are
+	// zero-valued when loaded from the mtrees flat-file format.
+	DescriptorUI string
+	ScopeNote    string
+	EntryTerms   []string
 }
 
 // Node is an element of the tree containing MeSH terms. It contains a Reference to a MeSH term, and any Children it
@@ -31,6 +38,10 @@ type Tree map[string]Node
 type MeSHTree struct {
 	Tree      Tree
 	Locations map[string][][]string
+
+	// trie indexes every TreeReference by its dotted tree-number code, to answer prefix and
+	// range queries without recursively walking Tree. See HasPrefix, WithPrefix, and Between.
+	trie *trieNode
 }
 
 // New loads a MeSH tree from a file.
@@ -53,6 +64,7 @@ func MeSHTreeFromReader(reader io.Reader) (*MeSHTree, error) {
 	tree := MeSHTree{
 		Tree:      make(Tree),
 		Locations: make(map[string][][]string),
+		trie:      &trieNode{},
 	}
 
 	scanner := bufio.NewScanner(reader)
@@ -75,6 +87,7 @@ func MeSHTreeFromReader(reader io.Reader) (*MeSHTree, error) {
 		// Remember the location for this heading.
 		normalisedHeading := strings.ToLower(ref.MedicalSubjectHeading)
 		tree.Locations[normalisedHeading] = append(tree.Locations[normalisedHeading], ref.TreeLocation)
+		tree.trie.insert(strings.Join(ref.TreeLocation, "."), ref)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -148,11 +161,9 @@ func (t MeSHTree) Reference(term string) []TreeReference {
 	var references []TreeReference
 	if locations, ok := t.Locations[strings.ToLower(term)]; ok {
 		for _, location := range locations {
-			ref, err := treeReferenceFromString(fmt.Sprintf("%s;%s", term, strings.Join(location, ".")))
-			if err != nil {
-				panic(err)
+			if node, ok := t.Tree.nodeAt(location); ok {
+				references = append(references, node.Reference)
 			}
-			references = append(references, ref)
 		}
 		return references
 	}
@@ -1,7 +1,9 @@
 package meshexp
 
 import (
+	"bytes"
 	"github.com/gin-gonic/gin/json"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +36,151 @@ func TestParent(t *testing.T) {
 
 	t.Log(tree.Parent("neuralgia, postherpetic"))
 }
+
+func TestTreeWriter(t *testing.T) {
+	tree, err := Default()
+	if err != nil {
+		t.Error(err)
+	}
+
+	var buf bytes.Buffer
+	w := TreeWriter{MaxDepth: 2, ShowCodes: true}
+	if err := w.Write(&buf, tree.Tree); err != nil {
+		t.Error(err)
+	}
+
+	t.Log(buf.String())
+}
+
+func TestDistance(t *testing.T) {
+	tree, err := Default()
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(tree.LowestCommonAncestor("neuralgia, postherpetic", "herpes zoster"))
+	t.Log(tree.Distance("neuralgia, postherpetic", "herpes zoster"))
+	t.Log(tree.NormalizedSimilarity("neuralgia, postherpetic", "herpes zoster"))
+}
+
+func TestWithPrefix(t *testing.T) {
+	tree, err := Default()
+	if err != nil {
+		t.Error(err)
+	}
+
+	t.Log(tree.HasPrefix("C01"))
+	t.Log(tree.WithPrefix("C01.463"))
+	t.Log(tree.Between("C01", "C02"))
+	t.Log(tree.ExplodeCode("C01.463"))
+}
+
+func TestDiff(t *testing.T) {
+	old, err := MeSHTreeFromReader(strings.NewReader(
+		"Pain;C23.888.592.612.705\n" +
+			"Neuralgia;C10.668.491.577\n" +
+			"Headache;C23.888.592.612.553\n",
+	))
+	if err != nil {
+		t.Error(err)
+	}
+
+	updated, err := MeSHTreeFromReader(strings.NewReader(
+		"Pain;C23.888.592.612.705\n" +
+			"Neuralgia;C10.668.491.577.500\n" +
+			"Cephalgia;C23.888.592.612.553\n" +
+			"Fatigue;C23.888.852.244\n",
+	))
+	if err != nil {
+		t.Error(err)
+	}
+
+	diff := Diff(old, updated)
+	if len(diff.Added) != 1 {
+		t.Errorf("expected 1 added heading, got %d", len(diff.Added))
+	}
+	if len(diff.Moved) != 1 {
+		t.Errorf("expected 1 moved heading, got %d", len(diff.Moved))
+	}
+	if len(diff.Renamed) != 1 {
+		t.Errorf("expected 1 renamed heading, got %d", len(diff.Renamed))
+	}
+
+	data, err := json.MarshalIndent(diff, "", "    ")
+	if err != nil {
+		t.Error(err)
+	}
+	t.Log(string(data))
+	t.Log(diff.Report())
+}
+
+func TestOverlay(t *testing.T) {
+	base, err := MeSHTreeFromReader(strings.NewReader(
+		"Neoplasms;C04\n" +
+			"Breast Neoplasms;C04.588\n",
+	))
+	if err != nil {
+		t.Error(err)
+	}
+
+	local, err := LoadOverlay(strings.NewReader(
+		"Triple Negative Breast Neoplasms;C04.588.443\n",
+	))
+	if err != nil {
+		t.Error(err)
+	}
+
+	merged := base.Overlay(local)
+	if !merged.Contains("triple negative breast neoplasms") {
+		t.Error("expected overlaid term to be reachable in the merged tree")
+	}
+	t.Log(merged.Parents("triple negative breast neoplasms"))
+
+	view := NewMergedTree(base, local)
+	if !view.Contains("triple negative breast neoplasms") {
+		t.Error("expected overlaid term to be reachable in the merged view")
+	}
+	if !view.Contains("breast neoplasms") {
+		t.Error("expected base term to be reachable in the merged view")
+	}
+}
+
+func TestOverlayOverridesHeading(t *testing.T) {
+	base, err := MeSHTreeFromReader(strings.NewReader("Foo;C04.588.443\n"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	override, err := LoadOverlay(strings.NewReader("Bar;C04.588.443\n"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	merged := base.Overlay(override)
+	if merged.Contains("foo") {
+		t.Error("expected the overridden heading to no longer be reachable")
+	}
+	if !merged.Contains("bar") {
+		t.Error("expected the overriding heading to be reachable")
+	}
+
+	refs := merged.WithPrefix("C04")
+	if len(refs) != 1 || refs[0].MedicalSubjectHeading != "Bar" {
+		t.Errorf("expected WithPrefix to return only the overriding heading, got %v", refs)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tree, err := Default()
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = tree.Walk("neuralgia, postherpetic", func(node Node, depth int) error {
+		t.Log(depth, node.Reference.MedicalSubjectHeading)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,123 @@
+package meshexp
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+)
+
+// descriptorRecordSet mirrors the top-level element of NLM's official descNNNN.xml
+// distribution, as described in https://www.nlm.nih.gov/mesh/xml_data_elements.html.
+type descriptorRecordSet struct {
+	XMLName           xml.Name           `xml:"DescriptorRecordSet"`
+	DescriptorRecords []descriptorRecord `xml:"DescriptorRecord"`
+}
+
+type descriptorRecord struct {
+	DescriptorUI   string         `xml:"DescriptorUI"`
+	DescriptorName descriptorName `xml:"DescriptorName"`
+	TreeNumberList treeNumberList `xml:"TreeNumberList"`
+	ConceptList    conceptList    `xml:"ConceptList"`
+}
+
+type descriptorName struct {
+	String string `xml:"String"`
+}
+
+type treeNumberList struct {
+	TreeNumbers []string `xml:"TreeNumber"`
+}
+
+type conceptList struct {
+	Concepts []concept `xml:"Concept"`
+}
+
+type concept struct {
+	PreferredConceptYN string   `xml:"PreferredConceptYN,attr"`
+	ScopeNote          string   `xml:"ScopeNote"`
+	TermList           termList `xml:"TermList"`
+}
+
+type termList struct {
+	Terms []term `xml:"Term"`
+}
+
+type term struct {
+	String string `xml:"String"`
+}
+
+// NewFromXML loads a MeSH tree from the official NLM XML distribution (descNNNN.xml).
+func NewFromXML(meshXMLFilepath string) (*MeSHTree, error) {
+	file, err := os.Open(meshXMLFilepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return MeSHTreeFromXMLReader(file)
+}
+
+// MeSHTreeFromXMLReader loads a MeSH tree from a reader over the official NLM XML
+// distribution, populating DescriptorUI, ScopeNote, and EntryTerms on each TreeReference.
+// Entry terms are also indexed in Locations alongside the preferred heading, so Contains,
+// Explode, and Reference all work when the caller supplies an entry term.
+func MeSHTreeFromXMLReader(reader io.Reader) (*MeSHTree, error) {
+	var recordSet descriptorRecordSet
+	if err := xml.NewDecoder(reader).Decode(&recordSet); err != nil {
+		return nil, err
+	}
+
+	tree := MeSHTree{
+		Tree:      make(Tree),
+		Locations: make(map[string][][]string),
+		trie:      &trieNode{},
+	}
+
+	for _, record := range recordSet.DescriptorRecords {
+		heading := record.DescriptorName.String
+		scopeNote, entryTerms := record.preferredConcept(heading)
+
+		for _, treeNumber := range record.TreeNumberList.TreeNumbers {
+			location := strings.Split(treeNumber, ".")
+			ref := TreeReference{
+				MedicalSubjectHeading: heading,
+				TreeLocation:          location,
+				DescriptorUI:          record.DescriptorUI,
+				ScopeNote:             scopeNote,
+				EntryTerms:            entryTerms,
+			}
+
+			// Unlike the mtrees flat-file format, a descriptor's TreeNumber may be several
+			// levels deep with no preceding record for its ancestors, so the tree is built
+			// with setReference (which creates every missing intermediate node) rather than
+			// the single-level addChild used by MeSHTreeFromReader.
+			tree.setReference(ref)
+
+			for _, synonym := range entryTerms {
+				normalisedSynonym := strings.ToLower(synonym)
+				tree.Locations[normalisedSynonym] = append(tree.Locations[normalisedSynonym], location)
+			}
+		}
+	}
+
+	return &tree, nil
+}
+
+// preferredConcept extracts the scope note and entry terms (synonyms) from the preferred
+// concept of a descriptor record, excluding the preferred heading itself from the entry terms.
+func (d descriptorRecord) preferredConcept(heading string) (scopeNote string, entryTerms []string) {
+	for _, c := range d.ConceptList.Concepts {
+		if c.PreferredConceptYN != "Y" {
+			continue
+		}
+		scopeNote = strings.TrimSpace(c.ScopeNote)
+		for _, t := range c.TermList.Terms {
+			if t.String == heading {
+				continue
+			}
+			entryTerms = append(entryTerms, t.String)
+		}
+		return
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package meshexp
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDescriptorXML = `<?xml version="1.0"?>
+<DescriptorRecordSet>
+	<DescriptorRecord>
+		<DescriptorUI>D002118</DescriptorUI>
+		<DescriptorName><String>Calcimycin</String></DescriptorName>
+		<TreeNumberList>
+			<TreeNumber>D03.633.100.221.173</TreeNumber>
+		</TreeNumberList>
+		<ConceptList>
+			<Concept PreferredConceptYN="Y">
+				<ScopeNote>An ionophorous, polyether antibiotic.</ScopeNote>
+				<TermList>
+					<Term><String>Calcimycin</String></Term>
+					<Term><String>A23187</String></Term>
+				</TermList>
+			</Concept>
+		</ConceptList>
+	</DescriptorRecord>
+</DescriptorRecordSet>
+`
+
+func TestMeSHTreeFromXMLReader(t *testing.T) {
+	tree, err := MeSHTreeFromXMLReader(strings.NewReader(testDescriptorXML))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !tree.Contains("calcimycin") {
+		t.Error("expected preferred heading to be contained in the tree")
+	}
+	if !tree.Contains("a23187") {
+		t.Error("expected entry term to be contained in the tree")
+	}
+
+	refs := tree.Reference("a23187")
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 reference for entry term, got %d", len(refs))
+	}
+	if refs[0].MedicalSubjectHeading != "Calcimycin" {
+		t.Errorf("expected entry term to resolve to the preferred heading, got %q", refs[0].MedicalSubjectHeading)
+	}
+	if refs[0].DescriptorUI != "D002118" {
+		t.Errorf("expected entry term to resolve to the descriptor's UI, got %q", refs[0].DescriptorUI)
+	}
+}
@@ -0,0 +1,174 @@
+package meshexp
+
+import (
+	"io"
+	"strings"
+)
+
+// LoadOverlay loads a local MeSH extension from a reader in the same heading;code format used
+// by MeSHTreeFromReader. The result is meant to be composed onto a base tree with Overlay or
+// NewMergedTree.
+func LoadOverlay(reader io.Reader) (*MeSHTree, error) {
+	return MeSHTreeFromReader(reader)
+}
+
+// Overlay composes t with other, returning a new MeSHTree containing the union of both: every
+// heading reachable in t or other is reachable in the result via Explode, Parents, Contains,
+// Depth, and Reference. When the same tree-number code exists in both trees, other's heading
+// and metadata take precedence.
+func (t *MeSHTree) Overlay(other *MeSHTree) *MeSHTree {
+	merged := &MeSHTree{
+		Tree:      make(Tree),
+		Locations: make(map[string][][]string),
+		trie:      &trieNode{},
+	}
+	for _, ref := range t.references() {
+		merged.setReference(ref)
+	}
+	for _, ref := range other.references() {
+		merged.setReference(ref)
+	}
+	return merged
+}
+
+// setReference inserts ref into the tree, creating any missing intermediate nodes, and
+// overwrites the Reference of any node that already exists at ref.TreeLocation. If that node
+// previously held a different heading, its Locations entry for this tree-number code is
+// dropped so the old heading stops resolving to a code it no longer owns.
+func (t *MeSHTree) setReference(ref TreeReference) {
+	if existing, ok := t.Tree.nodeAt(ref.TreeLocation); ok {
+		oldHeading := strings.ToLower(existing.Reference.MedicalSubjectHeading)
+		if oldHeading != strings.ToLower(ref.MedicalSubjectHeading) {
+			t.Locations[oldHeading] = removeLocation(t.Locations[oldHeading], ref.TreeLocation)
+			if len(t.Locations[oldHeading]) == 0 {
+				delete(t.Locations, oldHeading)
+			}
+		}
+	}
+
+	setNodeReference(t.Tree, ref.TreeLocation, nil, ref, 0)
+
+	normalisedHeading := strings.ToLower(ref.MedicalSubjectHeading)
+	t.Locations[normalisedHeading] = append(t.Locations[normalisedHeading], ref.TreeLocation)
+
+	code := strings.Join(ref.TreeLocation, ".")
+	if node := t.trie.find(code); node != nil {
+		// Drop any reference already stored at this exact code, so an override doesn't leave
+		// the shadowed heading behind alongside the new one.
+		node.refs = nil
+	}
+	t.trie.insert(code, ref)
+}
+
+// removeLocation returns locations with target removed.
+func removeLocation(locations [][]string, target []string) [][]string {
+	filtered := locations[:0]
+	for _, location := range locations {
+		if !equalLocation(location, target) {
+			filtered = append(filtered, location)
+		}
+	}
+	return filtered
+}
+
+func equalLocation(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// setNodeReference inserts ref at location, creating any missing intermediate nodes along the
+// way. An intermediate node created to fill a gap is stamped with its own partial TreeLocation
+// and no heading (it is a placeholder until a real reference for that exact code arrives);
+// only the terminal node at location gets ref itself. prefix accumulates the path already
+// walked so a freshly created placeholder's TreeLocation is correct rather than copied from
+// the leaf.
+func setNodeReference(tree Tree, location []string, prefix []string, ref TreeReference, depth int64) {
+	key := location[0]
+	path := append(append([]string{}, prefix...), key)
+
+	if len(location) == 1 {
+		if existing, ok := tree[key]; ok {
+			existing.Reference = ref
+			tree[key] = existing
+		} else {
+			tree[key] = Node{Reference: ref, Children: make(Tree), Depth: depth}
+		}
+		return
+	}
+
+	child, ok := tree[key]
+	if !ok {
+		child = Node{Reference: TreeReference{TreeLocation: path}, Children: make(Tree), Depth: depth}
+		tree[key] = child
+	}
+	setNodeReference(child.Children, location[1:], path, ref, depth+1)
+}
+
+// MergedTree is a lazy, read-only view over one or more MeSH trees composed in increasing
+// order of precedence: a term found in a later layer shadows the same term in an earlier one,
+// but a term found only in an earlier layer is still reachable. Unlike Overlay, MergedTree
+// does not copy or rebuild any tree; it simply asks each layer in turn.
+type MergedTree struct {
+	Layers []*MeSHTree
+}
+
+// NewMergedTree builds a MergedTree from layers, ordered from lowest to highest precedence
+// (typically the base MeSH tree followed by one or more local overlays).
+func NewMergedTree(layers ...*MeSHTree) *MergedTree {
+	return &MergedTree{Layers: layers}
+}
+
+// layerFor returns the highest-precedence layer that contains term, or nil if no layer does.
+func (m *MergedTree) layerFor(term string) *MeSHTree {
+	for i := len(m.Layers) - 1; i >= 0; i-- {
+		if m.Layers[i].Contains(term) {
+			return m.Layers[i]
+		}
+	}
+	return nil
+}
+
+// Contains indicates if term is contained within any layer.
+func (m *MergedTree) Contains(term string) bool {
+	return m.layerFor(term) != nil
+}
+
+// Explode extracts specific MeSH terms from a given MeSH term, deferring to whichever layer
+// contains term.
+func (m *MergedTree) Explode(term string) []string {
+	if layer := m.layerFor(term); layer != nil {
+		return layer.Explode(term)
+	}
+	return nil
+}
+
+// Depth extracts the depth at which term appears, deferring to whichever layer contains term.
+func (m *MergedTree) Depth(term string) int64 {
+	if layer := m.layerFor(term); layer != nil {
+		return layer.Depth(term)
+	}
+	return 0
+}
+
+// Parents finds the parents for term, deferring to whichever layer contains term.
+func (m *MergedTree) Parents(term string) []string {
+	if layer := m.layerFor(term); layer != nil {
+		return layer.Parents(term)
+	}
+	return nil
+}
+
+// Reference returns the TreeReferences for term, deferring to whichever layer contains term.
+func (m *MergedTree) Reference(term string) []TreeReference {
+	if layer := m.layerFor(term); layer != nil {
+		return layer.Reference(term)
+	}
+	return nil
+}
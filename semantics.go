@@ -0,0 +1,129 @@
+package meshexp
+
+import "strings"
+
+// Ancestors returns the full chain of headings from root to term, one chain per tree
+// location at which term occurs (a heading may appear at more than one location).
+func (t MeSHTree) Ancestors(term string) (chains [][]string) {
+	locations, ok := t.Locations[strings.ToLower(term)]
+	if !ok {
+		return nil
+	}
+	for _, location := range locations {
+		chains = append(chains, t.headingChain(location))
+	}
+	return
+}
+
+// LowestCommonAncestor returns the chain of headings from root to the lowest common ancestor
+// of a and b, i.e. the deepest heading under which both terms co-occur. Since either term may
+// occur at more than one tree location, every pair in Locations[a] x Locations[b] is
+// considered and the deepest shared prefix of tree-location codes wins. Returns nil if a and
+// b never co-occur in any tree.
+func (t MeSHTree) LowestCommonAncestor(a, b string) []string {
+	locationsA, ok := t.Locations[strings.ToLower(a)]
+	if !ok {
+		return nil
+	}
+	locationsB, ok := t.Locations[strings.ToLower(b)]
+	if !ok {
+		return nil
+	}
+
+	var best []string
+	for _, la := range locationsA {
+		for _, lb := range locationsB {
+			prefix := commonPrefix(la, lb)
+			if len(prefix) > len(best) {
+				best = prefix
+			}
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+	return t.headingChain(best)
+}
+
+// Distance returns the classic edge distance between a and b, depth(a)+depth(b)-2*depth(LCA).
+// It considers every pair in Locations[a] x Locations[b] and returns the smallest distance
+// found. Distance returns -1 if either term is not contained in the ontology.
+func (t MeSHTree) Distance(a, b string) int {
+	locationsA, ok := t.Locations[strings.ToLower(a)]
+	if !ok {
+		return -1
+	}
+	locationsB, ok := t.Locations[strings.ToLower(b)]
+	if !ok {
+		return -1
+	}
+
+	best := -1
+	for _, la := range locationsA {
+		for _, lb := range locationsB {
+			lcaDepth := len(commonPrefix(la, lb))
+			d := len(la) + len(lb) - 2*lcaDepth
+			if best == -1 || d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// NormalizedSimilarity returns the Wu-Palmer similarity between a and b, 2*depth(LCA)/
+// (depth(a)+depth(b)), in the range [0,1]. It considers every pair in Locations[a] x
+// Locations[b] and returns the highest similarity found. NormalizedSimilarity returns 0 if
+// either term is not contained in the ontology, or if a and b never co-occur in any tree.
+func (t MeSHTree) NormalizedSimilarity(a, b string) float64 {
+	locationsA, ok := t.Locations[strings.ToLower(a)]
+	if !ok {
+		return 0
+	}
+	locationsB, ok := t.Locations[strings.ToLower(b)]
+	if !ok {
+		return 0
+	}
+
+	var best float64
+	for _, la := range locationsA {
+		for _, lb := range locationsB {
+			lcaDepth := len(commonPrefix(la, lb))
+			if lcaDepth == 0 {
+				continue
+			}
+			sim := 2 * float64(lcaDepth) / float64(len(la)+len(lb))
+			if sim > best {
+				best = sim
+			}
+		}
+	}
+	return best
+}
+
+// headingChain resolves a tree-location code slice to the chain of headings from root to the
+// node at that location.
+func (t MeSHTree) headingChain(location []string) []string {
+	chain := make([]string, 0, len(location))
+	for i := range location {
+		node, ok := t.Tree.nodeAt(location[:i+1])
+		if !ok {
+			break
+		}
+		chain = append(chain, node.Reference.MedicalSubjectHeading)
+	}
+	return chain
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b []string) []string {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
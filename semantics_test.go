@@ -0,0 +1,130 @@
+package meshexp
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAncestorsThroughOverlay exercises Ancestors/Parents/LowestCommonAncestor/Distance over a
+// tree grown several levels below an already-loaded node via Overlay, the scenario in which an
+// intermediate node is created without ever being assigned its own real heading.
+func TestAncestorsThroughOverlay(t *testing.T) {
+	base, err := MeSHTreeFromReader(strings.NewReader(
+		"Neoplasms;C04\n" +
+			"Breast Neoplasms;C04.588\n" +
+			"Breast Neoplasms, Male;C04.588.443\n",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overlay, err := LoadOverlay(strings.NewReader("Custom Subtype A;C04.588.443.900.100\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := base.Overlay(overlay)
+
+	chains := merged.Ancestors("custom subtype a")
+	if len(chains) != 1 || len(chains[0]) != 5 {
+		t.Fatalf("expected a single 5-element ancestor chain, got %v", chains)
+	}
+	chain := chains[0]
+	if chain[2] != "Breast Neoplasms, Male" {
+		t.Errorf("expected the real ancestor at depth 3, got %q", chain[2])
+	}
+	if chain[4] != "Custom Subtype A" {
+		t.Errorf("expected the leaf heading at depth 5, got %q", chain[4])
+	}
+	for i, heading := range chain[:4] {
+		if heading == "Custom Subtype A" {
+			t.Errorf("leaf heading leaked into ancestor at depth %d: %v", i+1, chain)
+		}
+	}
+
+	parents := merged.Parents("custom subtype a")
+	if len(parents) != 1 || parents[0] == "Custom Subtype A" {
+		t.Errorf("expected Parents to not resolve to the term itself, got %v", parents)
+	}
+
+	lca := merged.LowestCommonAncestor("custom subtype a", "breast neoplasms, male")
+	if len(lca) == 0 || lca[len(lca)-1] != "Breast Neoplasms, Male" {
+		t.Errorf("expected LowestCommonAncestor to end at the shared ancestor, got %v", lca)
+	}
+
+	if d := merged.Distance("custom subtype a", "breast neoplasms, male"); d != 2 {
+		t.Errorf("expected Distance 2, got %d", d)
+	}
+
+	if sim := merged.NormalizedSimilarity("custom subtype a", "breast neoplasms, male"); sim <= 0 || sim >= 1 {
+		t.Errorf("expected NormalizedSimilarity in (0,1), got %v", sim)
+	}
+}
+
+// TestAncestorsThroughXML exercises the intermediate-node-creation path when loading real
+// descriptor XML whose records are not ordered by tree number, so a leaf is processed before
+// the ancestor record that owns an intermediate tree number.
+func TestAncestorsThroughXML(t *testing.T) {
+	tree, err := MeSHTreeFromXMLReader(strings.NewReader(`<?xml version="1.0"?>
+<DescriptorRecordSet>
+	<DescriptorRecord>
+		<DescriptorUI>D002118</DescriptorUI>
+		<DescriptorName><String>Calcimycin</String></DescriptorName>
+		<TreeNumberList>
+			<TreeNumber>D03.633.100.221.173</TreeNumber>
+		</TreeNumberList>
+		<ConceptList>
+			<Concept PreferredConceptYN="Y">
+				<TermList><Term><String>Calcimycin</String></Term></TermList>
+			</Concept>
+		</ConceptList>
+	</DescriptorRecord>
+	<DescriptorRecord>
+		<DescriptorUI>D008512</DescriptorUI>
+		<DescriptorName><String>Macrolides</String></DescriptorName>
+		<TreeNumberList>
+			<TreeNumber>D03.633.100</TreeNumber>
+		</TreeNumberList>
+		<ConceptList>
+			<Concept PreferredConceptYN="Y">
+				<TermList><Term><String>Macrolides</String></Term></TermList>
+			</Concept>
+		</ConceptList>
+	</DescriptorRecord>
+</DescriptorRecordSet>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chains := tree.Ancestors("calcimycin")
+	if len(chains) != 1 || len(chains[0]) != 5 {
+		t.Fatalf("expected a single 5-element ancestor chain, got %v", chains)
+	}
+	chain := chains[0]
+	if chain[2] != "Macrolides" {
+		t.Errorf("expected the real ancestor at depth 3, got %q", chain[2])
+	}
+	if chain[4] != "Calcimycin" {
+		t.Errorf("expected the leaf heading at depth 5, got %q", chain[4])
+	}
+	for i, heading := range chain[:4] {
+		if heading == "Calcimycin" {
+			t.Errorf("leaf heading leaked into ancestor at depth %d: %v", i+1, chain)
+		}
+	}
+
+	parents := tree.Parents("calcimycin")
+	if len(parents) != 1 || parents[0] == "Calcimycin" {
+		t.Errorf("expected Parents to not resolve to the term itself, got %v", parents)
+	}
+
+	lca := tree.LowestCommonAncestor("calcimycin", "macrolides")
+	if len(lca) == 0 || lca[len(lca)-1] != "Macrolides" {
+		t.Errorf("expected LowestCommonAncestor to end at Macrolides, got %v", lca)
+	}
+
+	if d := tree.Distance("calcimycin", "macrolides"); d != 2 {
+		t.Errorf("expected Distance 2, got %d", d)
+	}
+}
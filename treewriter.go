@@ -0,0 +1,115 @@
+package meshexp
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TreeWriter renders a Tree (the whole MeSHTree.Tree, or a subtree returned by Tree.At) as
+// an indented ASCII tree, analogous to kustomize's kio TreeWriter.
+type TreeWriter struct {
+	// MaxDepth limits how many levels are rendered below the starting point. Zero means
+	// unlimited.
+	MaxDepth int
+	// ShowCodes appends the tree-number code segment to each rendered heading.
+	ShowCodes bool
+	// Alphabetical sorts children by heading text rather than by tree-number code.
+	Alphabetical bool
+}
+
+// Write renders tree to out, starting at depth 0.
+func (w TreeWriter) Write(out io.Writer, tree Tree) error {
+	return w.write(out, tree, 0)
+}
+
+func (w TreeWriter) write(out io.Writer, tree Tree, depth int) error {
+	if w.MaxDepth > 0 && depth > w.MaxDepth {
+		return nil
+	}
+	for _, code := range w.order(tree) {
+		node := tree[code]
+		if _, err := fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", depth), w.line(code, node)); err != nil {
+			return err
+		}
+		if err := w.write(out, node.Children, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w TreeWriter) line(code string, node Node) string {
+	if w.ShowCodes {
+		return fmt.Sprintf("%s [%s]", node.Reference.MedicalSubjectHeading, code)
+	}
+	return node.Reference.MedicalSubjectHeading
+}
+
+func (w TreeWriter) order(tree Tree) []string {
+	codes := make([]string, 0, len(tree))
+	for code := range tree {
+		codes = append(codes, code)
+	}
+	if w.Alphabetical {
+		sort.Slice(codes, func(i, j int) bool {
+			return tree[codes[i]].Reference.MedicalSubjectHeading < tree[codes[j]].Reference.MedicalSubjectHeading
+		})
+	} else {
+		sort.Strings(codes)
+	}
+	return codes
+}
+
+// Walk performs a deterministic depth-first traversal from term, calling fn for the node at
+// term and every descendant. A heading may occur at more than one tree location, so Walk
+// traverses once per location.
+func (t MeSHTree) Walk(term string, fn func(node Node, depth int) error) error {
+	locations, ok := t.Locations[strings.ToLower(term)]
+	if !ok {
+		return nil
+	}
+	for _, location := range locations {
+		node, ok := t.Tree.nodeAt(location)
+		if !ok {
+			continue
+		}
+		if err := walk(node, 0, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walk(node Node, depth int, fn func(node Node, depth int) error) error {
+	if err := fn(node, depth); err != nil {
+		return err
+	}
+	codes := make([]string, 0, len(node.Children))
+	for code := range node.Children {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if err := walk(node.Children[code], depth+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeAt gets the Node at the specified location, unlike At which returns its Children.
+func (t Tree) nodeAt(location []string) (Node, bool) {
+	if len(location) == 0 {
+		return Node{}, false
+	}
+	node, ok := t[location[0]]
+	if !ok {
+		return Node{}, false
+	}
+	if len(location) == 1 {
+		return node, true
+	}
+	return node.Children.nodeAt(location[1:])
+}
@@ -0,0 +1,114 @@
+package meshexp
+
+import (
+	"sort"
+	"strings"
+)
+
+// trieNode is a node in a prefix tree over dotted MeSH tree-number codes (e.g. "C01.463.100").
+// Children are kept in a plain slice and scanned linearly, since branching factor is small.
+type trieNode struct {
+	char     byte
+	refs     []TreeReference
+	children []*trieNode
+}
+
+func (n *trieNode) child(char byte) *trieNode {
+	for _, c := range n.children {
+		if c.char == char {
+			return c
+		}
+	}
+	return nil
+}
+
+// insert adds ref under code, creating nodes as needed.
+func (n *trieNode) insert(code string, ref TreeReference) {
+	node := n
+	for i := 0; i < len(code); i++ {
+		c := node.child(code[i])
+		if c == nil {
+			c = &trieNode{char: code[i]}
+			node.children = append(node.children, c)
+		}
+		node = c
+	}
+	node.refs = append(node.refs, ref)
+}
+
+// find walks to the node reached by following code from n, or returns nil if no inserted
+// code has code as a prefix.
+func (n *trieNode) find(code string) *trieNode {
+	node := n
+	for i := 0; i < len(code); i++ {
+		c := node.child(code[i])
+		if c == nil {
+			return nil
+		}
+		node = c
+	}
+	return node
+}
+
+// collect returns every reference stored at or beneath n.
+func (n *trieNode) collect() (refs []TreeReference) {
+	refs = append(refs, n.refs...)
+	for _, c := range n.children {
+		refs = append(refs, c.collect()...)
+	}
+	return
+}
+
+// HasPrefix indicates whether any tree-number code in the ontology starts with code.
+func (t MeSHTree) HasPrefix(code string) bool {
+	if t.trie == nil {
+		return false
+	}
+	return t.trie.find(code) != nil
+}
+
+// WithPrefix returns every TreeReference whose tree-number code starts with code, sorted by
+// code for a deterministic result.
+func (t MeSHTree) WithPrefix(code string) []TreeReference {
+	if t.trie == nil {
+		return nil
+	}
+	node := t.trie.find(code)
+	if node == nil {
+		return nil
+	}
+	refs := node.collect()
+	sortReferencesByCode(refs)
+	return refs
+}
+
+// Between returns every TreeReference whose tree-number code falls lexicographically within
+// [lo, hi], sorted by code. It does not use the trie to prune the range; it filters a full
+// scan of every inserted reference, which is simple and correct but not sub-linear.
+func (t MeSHTree) Between(lo, hi string) []TreeReference {
+	if t.trie == nil {
+		return nil
+	}
+	var refs []TreeReference
+	for _, ref := range t.trie.collect() {
+		code := strings.Join(ref.TreeLocation, ".")
+		if code >= lo && code <= hi {
+			refs = append(refs, ref)
+		}
+	}
+	sortReferencesByCode(refs)
+	return refs
+}
+
+// ExplodeCode is the tree-number-code equivalent of Explode: it extracts every heading beneath
+// the given tree-number code, without requiring a round trip through a heading name. As with
+// Explode, the heading at the code itself is not included.
+func (t MeSHTree) ExplodeCode(code string) []string {
+	return t.Tree.At(strings.Split(code, ".")).Terms()
+}
+
+func sortReferencesByCode(refs []TreeReference) {
+	sort.Slice(refs, func(i, j int) bool {
+		return strings.Join(refs[i].TreeLocation, ".") < strings.Join(refs[j].TreeLocation, ".")
+	})
+}